@@ -5,18 +5,26 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"bskit/backend/auth"
+	"bskit/backend/buildbackend"
+	"bskit/backend/buildlog"
 	"bskit/backend/dagger"
+	"bskit/backend/dockerfile"
 	"bskit/backend/pack"
+	"bskit/backend/pipeline"
+	"bskit/backend/registry"
 	"bskit/backend/repo"
+	"bskit/backend/secrets"
 
+	"github.com/google/uuid"
 	"github.com/sqweek/dialog"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
-// TODO: refactor to use an interface based approach
 // App struct
 type App struct {
 	ctx          context.Context
@@ -26,6 +34,10 @@ type App struct {
 	Auth         *auth.Auth
 	repo         *repo.RepoManager
 	daggerRunner *dagger.Runner
+	backends     []buildbackend.Backend
+	buildLog     *buildlog.Logger
+	pipelines    *pipeline.Runner
+	secrets      *secrets.Store
 }
 
 // NewApp creates a new App application struct
@@ -49,15 +61,29 @@ func (a *App) Startup(ctx context.Context) {
 
 	fmt.Printf("Setting up event listeners...\n")
 
+	// Initialize structured build log streaming: every line also goes out
+	// as a build:log event so the frontend can tail live, while the ring
+	// buffer lets it replay history via GetBuildLogs after a refresh.
+	a.buildLog = buildlog.NewLogger(func(line buildlog.Line) {
+		runtime.EventsEmit(a.ctx, "build:log", line)
+	})
+
+	// Initialize the secret store used to expand ${VAR}/${VAR:-default}
+	// references in build inputs.
+	a.secrets = secrets.NewStore()
+
 	// Initialize auth with the correct context
 	a.Auth = auth.NewAuth(ctx)
 
-	// Initialize pack builder
-	var err error
-	a.packBuilder, err = pack.NewPackBuilder(ctx)
+	// Initialize pack builder. The pack CLI is optional: a user with only
+	// Docker/BuildKit installed still gets the Dockerfile and Kaniko
+	// backends, so a missing pack just omits buildpacks rather than
+	// aborting Startup.
+	packBuilder, err := pack.NewPackBuilder()
 	if err != nil {
-		log.Printf("Failed to initialize pack builder: %v", err)
-		return
+		log.Printf("pack CLI unavailable, omitting buildpacks backend: %v", err)
+	} else {
+		a.packBuilder = packBuilder
 	}
 
 	// Initialize dagger runner
@@ -67,6 +93,24 @@ func (a *App) Startup(ctx context.Context) {
 		return
 	}
 
+	// Register the available build backends, in auto-detect priority order.
+	if a.packBuilder != nil {
+		a.backends = append(a.backends, a.packBuilder)
+	}
+	a.backends = append(a.backends,
+		dockerfile.NewBuilder(),
+		dagger.NewKanikoBuilder(a.daggerRunner),
+	)
+
+	// The pipeline runner drives bskit.yaml's build/test/publish stages,
+	// pushing through the same registry client as PushImage.
+	a.pipelines = pipeline.NewRunner(a.backends, a.daggerRunner, a.buildLog, a.pushImageRef)
+
+	// Resume any persisted repo watches and start reporting new ones.
+	if err := a.repo.Start(a.ctx, a.onRepoUpdated, a.onRepoAutoBuild); err != nil {
+		log.Printf("Failed to start repo watch daemon: %v", err)
+	}
+
 	// Set up event listener for when frontend connects
 	runtime.EventsOn(a.eventCtx, "build:ready", func(data ...interface{}) {
 		fmt.Printf("Received build:ready event\n")
@@ -96,7 +140,9 @@ func (a *App) Startup(ctx context.Context) {
 		if len(data) > 0 {
 			if runData, ok := data[0].(map[string]interface{}); ok {
 				if imageName, ok := runData["imageName"].(string); ok {
-					if err := a.daggerRunner.RunContainer(imageName); err != nil {
+					runID := uuid.NewString()
+					a.buildLog.StartBuild(runID, "run:"+imageName)
+					if err := a.daggerRunner.RunContainer(imageName, a.buildLog, runID); err != nil {
 						runtime.EventsEmit(a.ctx, "build:log", fmt.Sprintf("Error: failed to run container: %v", err))
 					}
 				} else {
@@ -140,12 +186,200 @@ func (a *App) StartBuild(data map[string]interface{}) {
 		return
 	}
 
+	// Prefer a committed bskit.yaml over the single-shot backend build.
+	if p, ok, err := pipeline.LoadFromDir(absPath); err != nil {
+		runtime.EventsEmit(a.ctx, "build:log", fmt.Sprintf("Error: failed to load bskit.yaml: %v", err))
+		return
+	} else if ok {
+		branch, _ := data["branch"].(string)
+		if branch == "" {
+			branch = currentBranch(absPath)
+		}
+		event, _ := data["event"].(string)
+		if event == "" {
+			event = "manual"
+		}
+		a.runPipeline(absPath, p, branch, event)
+		return
+	}
+
+	// Pick the backend: honor an explicit choice from the frontend, otherwise
+	// auto-detect from the project layout (Dockerfile, project.toml, ...).
+	var chosen buildbackend.Backend
+	if name, ok := data["backend"].(string); ok && name != "" {
+		chosen = buildbackend.ByName(a.backends, name)
+		if chosen == nil {
+			runtime.EventsEmit(a.ctx, "build:log", fmt.Sprintf("Error: unknown build backend %q.", name))
+			return
+		}
+	} else {
+		chosen = buildbackend.Select(a.backends, absPath)
+		if chosen == nil {
+			runtime.EventsEmit(a.ctx, "build:log", "Error: could not detect a build backend for the selected directory.")
+			return
+		}
+	}
+	runtime.EventsEmit(a.ctx, "build:backend", chosen.Name())
+
 	// Start the build process
-	if err := a.packBuilder.Build(absPath, platform); err != nil {
+	buildID := uuid.NewString()
+	a.buildLog.StartBuild(buildID, chosen.Name())
+	opts := buildbackend.Options{
+		Platform:  platform,
+		BuildID:   buildID,
+		Logger:    a.buildLog,
+		BuildArgs: a.secrets.ExpandMap(stringMap(data["buildArgs"])),
+	}
+	if err := chosen.Build(a.ctx, absPath, opts); err != nil {
 		runtime.EventsEmit(a.ctx, "build:log", fmt.Sprintf("Error: build failed: %v", err))
 	}
 }
 
+// stringMap converts a build:start payload field (decoded by Wails as
+// map[string]interface{}) into a map[string]string, skipping non-string
+// values.
+func stringMap(v interface{}) map[string]string {
+	raw, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	out := make(map[string]string, len(raw))
+	for k, val := range raw {
+		if s, ok := val.(string); ok {
+			out[k] = s
+		}
+	}
+	return out
+}
+
+// runPipeline executes p against absPath, emitting pipeline:step:{start,end}
+// events with each step's name and duration so the frontend can render a
+// stage view. branch and event gate each stage's `when:` block, see
+// pipeline.Runner.Execute.
+func (a *App) runPipeline(absPath string, p *pipeline.Pipeline, branch, event string) {
+	p.ExpandWith(a.secrets.Expand)
+
+	buildID := uuid.NewString()
+	a.buildLog.StartBuild(buildID, "pipeline")
+
+	onStep := func(e pipeline.StepEvent) {
+		runtime.EventsEmit(a.ctx, "pipeline:step:"+e.Phase, map[string]interface{}{
+			"step":     e.Step,
+			"duration": e.Duration.String(),
+		})
+	}
+
+	if err := a.pipelines.Execute(a.ctx, absPath, p, buildID, branch, event, onStep); err != nil {
+		runtime.EventsEmit(a.ctx, "build:log", fmt.Sprintf("Error: pipeline failed: %v", err))
+	}
+}
+
+// currentBranch returns the checked-out branch at absPath, or "" if it
+// can't be determined (not a git repo, detached HEAD, git missing, ...).
+func currentBranch(absPath string) string {
+	out, err := exec.Command("git", "-C", absPath, "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// PushImage parses imageRef and pushes it to the matching registry, using
+// credentials resolved via Auth. Progress streams through the structured
+// build log and a push:layer event per layer reported.
+func (a *App) PushImage(imageRef string) error {
+	return a.pushImageRef(a.ctx, imageRef)
+}
+
+// pushImageRef is the shared implementation behind PushImage and the
+// pipeline's publish stage.
+func (a *App) pushImageRef(ctx context.Context, imageRef string) error {
+	ref, err := registry.ParseRef(a.secrets.Expand(imageRef))
+	if err != nil {
+		return fmt.Errorf("invalid image reference %q: %w", imageRef, err)
+	}
+
+	buildID := uuid.NewString()
+	a.buildLog.StartBuild(buildID, "push:"+ref.Registry)
+
+	onLayer := func(lp registry.LayerProgress) {
+		runtime.EventsEmit(a.ctx, "push:layer", map[string]interface{}{
+			"layer":    lp.Layer,
+			"status":   lp.Status,
+			"progress": lp.Progress,
+		})
+	}
+
+	return registry.Push(ctx, a.Auth, ref, a.buildLog, buildID, onLayer)
+}
+
+// SetRepoWatch starts (or reconfigures) background polling of repoPath for
+// new commits on branches, every intervalSec seconds. Passing intervalSec
+// of 0 stops and removes the watch. When autoBuild is set, an advancing
+// branch triggers StartBuild automatically.
+func (a *App) SetRepoWatch(repoPath string, branches []string, intervalSec int, autoBuild bool) error {
+	return a.repo.SetRepoWatch(a.ctx, repoPath, branches, intervalSec, autoBuild)
+}
+
+// GetRepoWatch returns the watch configuration for repoPath, if any.
+func (a *App) GetRepoWatch(repoPath string) (*repo.WatchConfig, error) {
+	return a.repo.GetRepoWatch(repoPath)
+}
+
+// onRepoUpdated forwards a repo watch's detected branch advance to the
+// frontend as a repo:updated event.
+func (a *App) onRepoUpdated(update repo.RepoUpdate) {
+	runtime.EventsEmit(a.ctx, "repo:updated", map[string]interface{}{
+		"repoPath": update.RepoPath,
+		"branch":   update.Branch,
+		"oldSHA":   update.OldSHA,
+		"newSHA":   update.NewSHA,
+	})
+}
+
+// onRepoAutoBuild triggers a build for a watch configured with autoBuild.
+// There's no frontend request to read a platform from here, so it defaults
+// to amd64. The triggering branch is threaded through so a pipeline's
+// `when:` blocks can gate on it; event is always "push" since this only
+// fires when the watch daemon observes the branch advance.
+func (a *App) onRepoAutoBuild(repoPath, branch string) {
+	a.StartBuild(map[string]interface{}{
+		"selectedDirectory": repoPath,
+		"platform":          "amd64",
+		"branch":            branch,
+		"event":             "push",
+	})
+}
+
+// SetSecret stores value under name in the OS keyring. Once set, its value
+// is never returned to the frontend again, only its name via GetSecretNames.
+func (a *App) SetSecret(name, value string) error {
+	return a.secrets.Set(name, value)
+}
+
+// GetSecretNames lists the names of every stored secret, without values.
+func (a *App) GetSecretNames() ([]string, error) {
+	return a.secrets.List()
+}
+
+// DeleteSecret removes the secret stored under name.
+func (a *App) DeleteSecret(name string) error {
+	return a.secrets.Delete(name)
+}
+
+// GetBuildLogs returns the log lines recorded for buildID since sinceSeq,
+// so the frontend can reconnect after a refresh and replay what it missed.
+func (a *App) GetBuildLogs(buildID string, sinceSeq int) ([]buildlog.Line, error) {
+	return a.buildLog.GetBuildLogs(buildID, sinceSeq)
+}
+
+// ListBuilds returns a summary of every build, live or finished, so the
+// frontend can offer a log viewer across builds rather than just the
+// current one.
+func (a *App) ListBuilds() []buildlog.BuildSummary {
+	return a.buildLog.ListBuilds()
+}
+
 // SelectDirectory opens a directory selection dialog and returns the selected path
 func (a *App) SelectDirectory() string {
 	selectedDirectory, err := dialog.Directory().Title("Select Directory").Browse()
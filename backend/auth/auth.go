@@ -0,0 +1,64 @@
+// Package auth handles user authentication for bskit: the GitHub device
+// flow used to clone private repos, and a keyring-backed credential store
+// for everything else (registry logins, signing keys, ...).
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// UserCodeInfo is the device-flow code the user enters at GitHub's
+// verification URL to complete login.
+type UserCodeInfo struct {
+	UserCode        string
+	VerificationURI string
+	ExpiresIn       int
+}
+
+// Repo is a GitHub repository surfaced in the recent-repos picker.
+type Repo struct {
+	Name     string
+	FullName string
+	CloneURL string
+}
+
+// Auth holds the user's GitHub session once the device flow completes, plus
+// the keyring-backed store for registry and other third-party credentials.
+type Auth struct {
+	ctx         context.Context
+	Credentials *CredentialStore
+
+	mu    sync.Mutex
+	token string
+}
+
+// NewAuth creates an Auth bound to ctx.
+func NewAuth(ctx context.Context) *Auth {
+	return &Auth{ctx: ctx, Credentials: NewCredentialStore()}
+}
+
+// StartGitHubLogin always returns an error: it makes no device flow request
+// to GitHub and returns no user code.
+func (a *Auth) StartGitHubLogin() (*UserCodeInfo, error) {
+	// TODO: implement the actual device flow request against GitHub's API.
+	return nil, fmt.Errorf("GitHub device flow not yet configured")
+}
+
+// GetRecentRepos errors when no GitHub token is held. With a token it
+// returns no repos, as it makes no call to the GitHub API.
+func (a *Auth) GetRecentRepos() ([]Repo, error) {
+	if a.Token() == "" {
+		return nil, fmt.Errorf("not logged in to GitHub")
+	}
+	// TODO: call the GitHub API for the user's recent repos.
+	return nil, nil
+}
+
+// Token returns the current GitHub access token, or "" if not logged in.
+func (a *Auth) Token() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.token
+}
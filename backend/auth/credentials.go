@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/zalando/go-keyring"
+)
+
+// keyringService namespaces bskit's entries in the OS keyring so they don't
+// collide with other apps' secrets.
+const keyringService = "bskit"
+
+// RegistryCredential is a username/password pair for a single registry host.
+type RegistryCredential struct {
+	Username string
+	Password string
+}
+
+// CredentialStore persists registry credentials in the OS keyring, so they
+// never need to pass through the Wails IPC payload once set.
+type CredentialStore struct{}
+
+// NewCredentialStore returns a CredentialStore backed by the OS keyring.
+func NewCredentialStore() *CredentialStore {
+	return &CredentialStore{}
+}
+
+// SetRegistryCredential stores username/password for host, overwriting any
+// existing entry.
+func (s *CredentialStore) SetRegistryCredential(host string, cred RegistryCredential) error {
+	data, err := json.Marshal(cred)
+	if err != nil {
+		return fmt.Errorf("failed to encode credential for %s: %w", host, err)
+	}
+	if err := keyring.Set(keyringService, registryKey(host), string(data)); err != nil {
+		return fmt.Errorf("failed to store credential for %s: %w", host, err)
+	}
+	return nil
+}
+
+// GetRegistryCredential returns the stored credential for host, if any.
+func (s *CredentialStore) GetRegistryCredential(host string) (RegistryCredential, bool, error) {
+	data, err := keyring.Get(keyringService, registryKey(host))
+	if err == keyring.ErrNotFound {
+		return RegistryCredential{}, false, nil
+	}
+	if err != nil {
+		return RegistryCredential{}, false, fmt.Errorf("failed to read credential for %s: %w", host, err)
+	}
+	var cred RegistryCredential
+	if err := json.Unmarshal([]byte(data), &cred); err != nil {
+		return RegistryCredential{}, false, fmt.Errorf("failed to decode credential for %s: %w", host, err)
+	}
+	return cred, true, nil
+}
+
+// DeleteRegistryCredential removes the stored credential for host, if any.
+func (s *CredentialStore) DeleteRegistryCredential(host string) error {
+	if err := keyring.Delete(keyringService, registryKey(host)); err != nil && err != keyring.ErrNotFound {
+		return fmt.Errorf("failed to delete credential for %s: %w", host, err)
+	}
+	return nil
+}
+
+func registryKey(host string) string {
+	return "registry:" + host
+}
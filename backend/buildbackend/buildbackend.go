@@ -0,0 +1,63 @@
+// Package buildbackend defines the shared interface implemented by every
+// image-construction strategy bskit can drive (Buildpacks, Dockerfile/BuildKit,
+// Kaniko, ...), so the frontend can pick one without the App needing to know
+// the details of each.
+package buildbackend
+
+import (
+	"context"
+
+	"bskit/backend/buildlog"
+)
+
+// Options carries the per-build parameters common to all backends.
+type Options struct {
+	// Platform is the target architecture, e.g. "arm64" or "amd64".
+	Platform string
+	// BuildID correlates this build's output in Logger, see backend/buildlog.
+	BuildID string
+	// Logger receives the backend's output. When nil, the pack and
+	// dockerfile backends write to the process's stdout/stderr and the
+	// kaniko backend drops its output.
+	Logger *buildlog.Logger
+	// BuildArgs are forwarded to the backend as build-time environment
+	// variables. Callers expand secret references before setting this, see
+	// backend/secrets.
+	BuildArgs map[string]string
+	// ImageTag, if set, is appended to the backend's local image name
+	// (e.g. "myapp-arm64"). It's used when fanning the same build out
+	// across multiple platforms, so each iteration produces a distinct
+	// image instead of overwriting the last one.
+	ImageTag string
+}
+
+// Backend is an image-construction strategy selectable from the frontend.
+type Backend interface {
+	// Name identifies the backend for logging and the build:backend event.
+	Name() string
+	// Detect reports whether this backend applies to the project at absPath.
+	Detect(absPath string) bool
+	// Build runs the build, streaming progress until ctx is done or it returns.
+	Build(ctx context.Context, absPath string, opts Options) error
+}
+
+// Select returns the first backend in backends that detects absPath.
+// It's used to auto-pick a strategy when the frontend doesn't name one.
+func Select(backends []Backend, absPath string) Backend {
+	for _, b := range backends {
+		if b.Detect(absPath) {
+			return b
+		}
+	}
+	return nil
+}
+
+// ByName returns the backend in backends whose Name matches, or nil.
+func ByName(backends []Backend, name string) Backend {
+	for _, b := range backends {
+		if b.Name() == name {
+			return b
+		}
+	}
+	return nil
+}
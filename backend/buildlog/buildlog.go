@@ -0,0 +1,176 @@
+// Package buildlog provides structured, level-based log streaming for
+// builds, backed by a per-build ring buffer so the frontend can replay
+// history after a refresh instead of only tailing a live firehose.
+package buildlog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a log Line, modeled on logrus-style levels.
+type Level int
+
+const (
+	Debug Level = iota
+	Info
+	Warn
+	Error
+)
+
+// String renders the level the way the frontend expects to display it.
+func (l Level) String() string {
+	switch l {
+	case Debug:
+		return "debug"
+	case Warn:
+		return "warn"
+	case Error:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+// MarshalJSON renders the level as its String() form, so Line serializes
+// over the wire (GetBuildLogs, the build:log event) as "info"/"error"/...
+// rather than the underlying int.
+func (l Level) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.String())
+}
+
+// maxLinesPerBuild bounds the ring buffer so long-running builds don't grow
+// memory unbounded; older lines are dropped once a build exceeds this.
+const maxLinesPerBuild = 2000
+
+// Line is a single structured log entry, correlated to a build via BuildID.
+type Line struct {
+	Seq     int
+	Level   Level
+	Time    time.Time
+	Phase   string
+	BuildID string
+	Msg     string
+}
+
+// BuildSummary describes a build the Logger has seen, for ListBuilds.
+type BuildSummary struct {
+	BuildID   string
+	Backend   string
+	StartedAt time.Time
+}
+
+// Logger records build output into per-build ring buffers and notifies
+// onLine (if set) of each line as it's written, for live tailing.
+type Logger struct {
+	mu     sync.Mutex
+	lines  map[string][]Line
+	seq    map[string]int
+	builds map[string]BuildSummary
+	onLine func(Line)
+}
+
+// NewLogger creates a Logger. onLine, if non-nil, is called synchronously
+// for every line written, e.g. to forward it over the event bus.
+func NewLogger(onLine func(Line)) *Logger {
+	return &Logger{
+		lines:  make(map[string][]Line),
+		seq:    make(map[string]int),
+		builds: make(map[string]BuildSummary),
+		onLine: onLine,
+	}
+}
+
+// StartBuild records that buildID has started against backend, so it shows
+// up in ListBuilds even before its first log line arrives.
+func (l *Logger) StartBuild(buildID, backend string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.builds[buildID] = BuildSummary{BuildID: buildID, Backend: backend, StartedAt: time.Now()}
+}
+
+// Write appends line to buildID's ring buffer, assigning it the next
+// sequence number, and forwards it to onLine.
+func (l *Logger) Write(buildID string, level Level, phase, msg string) Line {
+	l.mu.Lock()
+	l.seq[buildID]++
+	line := Line{
+		Seq:     l.seq[buildID],
+		Level:   level,
+		Time:    time.Now(),
+		Phase:   phase,
+		BuildID: buildID,
+		Msg:     msg,
+	}
+	buf := append(l.lines[buildID], line)
+	if len(buf) > maxLinesPerBuild {
+		buf = buf[len(buf)-maxLinesPerBuild:]
+	}
+	l.lines[buildID] = buf
+	l.mu.Unlock()
+
+	if l.onLine != nil {
+		l.onLine(line)
+	}
+	return line
+}
+
+// GetBuildLogs returns the lines recorded for buildID with Seq > sinceSeq,
+// so a reconnecting frontend can replay only what it missed. It errors if
+// buildID was never registered with StartBuild.
+func (l *Logger) GetBuildLogs(buildID string, sinceSeq int) ([]Line, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.builds[buildID]; !ok {
+		return nil, fmt.Errorf("unknown build id: %s", buildID)
+	}
+
+	all := l.lines[buildID]
+	out := make([]Line, 0, len(all))
+	for _, line := range all {
+		if line.Seq > sinceSeq {
+			out = append(out, line)
+		}
+	}
+	return out, nil
+}
+
+// ListBuilds returns a summary of every build the Logger has seen.
+func (l *Logger) ListBuilds() []BuildSummary {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	out := make([]BuildSummary, 0, len(l.builds))
+	for _, b := range l.builds {
+		out = append(out, b)
+	}
+	return out
+}
+
+// Writer returns an io.Writer that splits whatever is written to it into
+// lines and records each as an Info-level Line tagged with phase. It's
+// meant to be plugged in as a command's Stdout/Stderr.
+func (l *Logger) Writer(buildID, phase string) *phaseWriter {
+	return &phaseWriter{logger: l, buildID: buildID, phase: phase}
+}
+
+// phaseWriter adapts a Logger into an io.Writer for a fixed build/phase.
+type phaseWriter struct {
+	logger  *Logger
+	buildID string
+	phase   string
+}
+
+func (w *phaseWriter) Write(p []byte) (int, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(p))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		w.logger.Write(w.buildID, Info, w.phase, scanner.Text())
+	}
+	return len(p), nil
+}
@@ -0,0 +1,93 @@
+package dagger
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"bskit/backend/buildbackend"
+	"bskit/backend/buildlog"
+)
+
+// kanikoImage is the executor image used to build without a Docker daemon.
+const kanikoImage = "gcr.io/kaniko-project/executor:latest"
+
+// KanikoBuilder validates a Dockerfile-based project builds with Kaniko
+// inside a Dagger container, giving a rootless, in-cluster-style check.
+// It runs with --no-push and exports nothing, so unlike PackBuilder and the
+// Dockerfile backend it produces no image a later test/publish stage can
+// reference by name — use it to validate a build, not to feed the rest of
+// a pipeline. Unlike those backends it's also never auto-detected; it's
+// only used when the frontend or a bskit.yaml step selects it explicitly.
+type KanikoBuilder struct {
+	runner *Runner
+}
+
+// NewKanikoBuilder wraps runner as a buildbackend.Backend.
+func NewKanikoBuilder(runner *Runner) *KanikoBuilder {
+	return &KanikoBuilder{runner: runner}
+}
+
+// Name identifies this backend for the build:backend event.
+func (k *KanikoBuilder) Name() string {
+	return "kaniko"
+}
+
+// Detect always returns false: Kaniko is opt-in only, see KanikoBuilder.
+func (k *KanikoBuilder) Detect(absPath string) bool {
+	return false
+}
+
+// Build runs the Kaniko executor over absPath inside a Dagger container.
+// It only validates the build (--no-push, no --tarPath/--destination); see
+// KanikoBuilder's doc comment.
+func (k *KanikoBuilder) Build(ctx context.Context, absPath string, opts buildbackend.Options) error {
+	src := k.runner.client.Host().Directory(absPath)
+
+	args := []string{
+		"--dockerfile=/workspace/Dockerfile",
+		"--context=/workspace",
+		"--no-push",
+	}
+	if opts.Platform != "" {
+		args = append(args, "--customPlatform=linux/"+opts.Platform)
+	}
+	for _, key := range sortedKeys(opts.BuildArgs) {
+		args = append(args, fmt.Sprintf("--build-arg=%s=%s", key, opts.BuildArgs[key]))
+	}
+
+	container := k.runner.client.Container().
+		From(kanikoImage).
+		WithMountedDirectory("/workspace", src).
+		WithWorkdir("/workspace").
+		WithExec(args)
+
+	out, err := container.Stdout(ctx)
+	k.streamOutput(opts, out)
+	if err != nil {
+		return fmt.Errorf("kaniko build failed: %w", err)
+	}
+	return nil
+}
+
+// streamOutput forwards out line-by-line to opts.Logger, tagged "build".
+func (k *KanikoBuilder) streamOutput(opts buildbackend.Options, out string) {
+	if opts.Logger == nil || out == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		opts.Logger.Write(opts.BuildID, buildlog.Info, "build", line)
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var _ buildbackend.Backend = (*KanikoBuilder)(nil)
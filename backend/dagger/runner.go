@@ -0,0 +1,60 @@
+// Package dagger drives containerized workloads through the Dagger engine:
+// running built images and, for the Kaniko backend, building them rootless
+// and in-cluster-style.
+package dagger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"dagger.io/dagger"
+
+	"bskit/backend/buildlog"
+)
+
+// Runner holds the Dagger client used to run and build containers.
+type Runner struct {
+	ctx    context.Context
+	client *dagger.Client
+}
+
+// NewRunner connects to the Dagger engine and returns a Runner bound to ctx.
+func NewRunner(ctx context.Context) (*Runner, error) {
+	client, err := dagger.Connect(ctx, dagger.WithLogOutput(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to dagger engine: %w", err)
+	}
+	return &Runner{ctx: ctx, client: client}, nil
+}
+
+// RunContainer runs imageName to completion. If logger is non-nil, its
+// stdout is streamed line-by-line tagged with the "run" phase under buildID.
+func (r *Runner) RunContainer(imageName string, logger *buildlog.Logger, buildID string) error {
+	out, err := r.client.Container().From(imageName).WithExec(nil).Stdout(r.ctx)
+	if logger != nil && out != "" {
+		for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+			logger.Write(buildID, buildlog.Info, "run", line)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run container %s: %w", imageName, err)
+	}
+	return nil
+}
+
+// RunCommand runs command inside imageName, e.g. for the pipeline test
+// stage. If logger is non-nil, its stdout is streamed line-by-line tagged
+// with the "test" phase under buildID.
+func (r *Runner) RunCommand(imageName string, command []string, logger *buildlog.Logger, buildID string) error {
+	out, err := r.client.Container().From(imageName).WithExec(command).Stdout(r.ctx)
+	if logger != nil && out != "" {
+		for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+			logger.Write(buildID, buildlog.Info, "test", line)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run command in %s: %w", imageName, err)
+	}
+	return nil
+}
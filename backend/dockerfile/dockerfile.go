@@ -0,0 +1,98 @@
+// Package dockerfile builds images from a plain Dockerfile using BuildKit's
+// buildctl (falling back to `docker build`), as an alternative to the
+// Buildpacks and Kaniko backends.
+package dockerfile
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"bskit/backend/buildbackend"
+)
+
+// Builder builds images by shelling out to buildctl or docker build.
+type Builder struct{}
+
+// NewBuilder returns a Dockerfile Builder.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Name identifies this backend for the build:backend event.
+func (b *Builder) Name() string {
+	return "dockerfile"
+}
+
+// Detect reports whether absPath contains a Dockerfile.
+func (b *Builder) Detect(absPath string) bool {
+	_, err := os.Stat(filepath.Join(absPath, "Dockerfile"))
+	return err == nil
+}
+
+// Build runs the build, preferring buildctl (BuildKit) and falling back to
+// `docker build` when buildctl isn't available. Each call builds one
+// platform, linux/<opts.Platform> (linux/amd64 when opts.Platform is empty).
+func (b *Builder) Build(ctx context.Context, absPath string, opts buildbackend.Options) error {
+	imageName := filepath.Base(absPath)
+	if opts.ImageTag != "" {
+		imageName += "-" + opts.ImageTag
+	}
+	platform := "linux/amd64"
+	if opts.Platform != "" {
+		platform = "linux/" + opts.Platform
+	}
+
+	var cmd *exec.Cmd
+	if _, err := exec.LookPath("buildctl"); err == nil {
+		args := []string{"build",
+			"--frontend", "dockerfile.v0",
+			"--local", "context=" + absPath,
+			"--local", "dockerfile=" + absPath,
+			"--opt", "platform=" + platform,
+		}
+		for _, key := range sortedKeys(opts.BuildArgs) {
+			args = append(args, "--opt", fmt.Sprintf("build-arg:%s=%s", key, opts.BuildArgs[key]))
+		}
+		args = append(args, "--output", fmt.Sprintf("type=image,name=%s", imageName))
+		cmd = exec.CommandContext(ctx, "buildctl", args...)
+	} else {
+		args := []string{"build",
+			"--platform", platform,
+			"-t", imageName,
+		}
+		for _, key := range sortedKeys(opts.BuildArgs) {
+			args = append(args, "--build-arg", fmt.Sprintf("%s=%s", key, opts.BuildArgs[key]))
+		}
+		args = append(args, absPath)
+		cmd = exec.CommandContext(ctx, "docker", args...)
+	}
+	cmd.Dir = absPath
+	if opts.Logger != nil {
+		w := opts.Logger.Writer(opts.BuildID, "build")
+		cmd.Stdout = w
+		cmd.Stderr = w
+	} else {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("dockerfile build failed: %w", err)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var _ buildbackend.Backend = (*Builder)(nil)
@@ -0,0 +1,79 @@
+// Package pack drives the Cloud Native Buildpacks CLI ("pack build") to turn
+// a source directory into a runnable image without a Dockerfile.
+package pack
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"bskit/backend/buildbackend"
+)
+
+// PackBuilder runs `pack build` against a project directory.
+type PackBuilder struct{}
+
+// NewPackBuilder returns a PackBuilder, erroring if the pack CLI isn't on
+// PATH.
+func NewPackBuilder() (*PackBuilder, error) {
+	if _, err := exec.LookPath("pack"); err != nil {
+		return nil, fmt.Errorf("pack CLI not found in PATH: %w", err)
+	}
+	return &PackBuilder{}, nil
+}
+
+// Name identifies this backend for the build:backend event.
+func (p *PackBuilder) Name() string {
+	return "buildpacks"
+}
+
+// Detect reports whether absPath looks like a buildpacks project, i.e. it
+// already declares a builder via project.toml rather than a Dockerfile.
+func (p *PackBuilder) Detect(absPath string) bool {
+	_, err := os.Stat(filepath.Join(absPath, "project.toml"))
+	return err == nil
+}
+
+// Build runs `pack build` for absPath, targeting opts.Platform.
+func (p *PackBuilder) Build(ctx context.Context, absPath string, opts buildbackend.Options) error {
+	imageName := filepath.Base(absPath)
+	if opts.ImageTag != "" {
+		imageName += "-" + opts.ImageTag
+	}
+
+	args := []string{"build", imageName, "--path", absPath, "--builder", "paketobuildpacks/builder-jammy-base"}
+	if opts.Platform != "" {
+		args = append(args, "--platform", fmt.Sprintf("linux/%s", opts.Platform))
+	}
+	for _, key := range sortedKeys(opts.BuildArgs) {
+		args = append(args, "--env", fmt.Sprintf("%s=%s", key, opts.BuildArgs[key]))
+	}
+
+	cmd := exec.CommandContext(ctx, "pack", args...)
+	cmd.Dir = absPath
+	if opts.Logger != nil {
+		w := opts.Logger.Writer(opts.BuildID, "build")
+		cmd.Stdout = w
+		cmd.Stderr = w
+	} else {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pack build failed: %w", err)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,97 @@
+// Package pipeline loads and executes a declarative `bskit.yaml`, letting a
+// repo describe ordered build/test/publish stages instead of relying on a
+// single pack build triggered from the frontend.
+package pipeline
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the pipeline file bskit looks for in a selected directory.
+const FileName = "bskit.yaml"
+
+// When restricts a Step to certain branches or trigger events, mirroring
+// Drone/Woodpecker's `when:` block.
+type When struct {
+	Branch string `yaml:"branch,omitempty"`
+	Event  string `yaml:"event,omitempty"`
+}
+
+// Step is one stage of the pipeline. Which fields apply depends on which
+// of Pipeline's stages it's attached to: Build uses Backend/Platforms,
+// Test uses Image/Command, Publish uses Image/Registry.
+type Step struct {
+	Name      string   `yaml:"name,omitempty"`
+	Backend   string   `yaml:"backend,omitempty"`
+	Platforms []string `yaml:"platforms,omitempty"`
+	Image     string   `yaml:"image,omitempty"`
+	Command   []string `yaml:"command,omitempty"`
+	Registry  string   `yaml:"registry,omitempty"`
+	When      *When    `yaml:"when,omitempty"`
+}
+
+// Pipeline is the parsed contents of a bskit.yaml file.
+type Pipeline struct {
+	Build   *Step `yaml:"build,omitempty"`
+	Test    *Step `yaml:"test,omitempty"`
+	Publish *Step `yaml:"publish,omitempty"`
+}
+
+// Matches reports whether a step should run for the given branch/event,
+// treating a nil When (or a nil step) as "always run".
+func (s *Step) Matches(branch, event string) bool {
+	if s == nil || s.When == nil {
+		return true
+	}
+	if s.When.Branch != "" && s.When.Branch != branch {
+		return false
+	}
+	if s.When.Event != "" && s.When.Event != event {
+		return false
+	}
+	return true
+}
+
+// ExpandWith rewrites every command, image, and registry field in p using
+// expand, e.g. to resolve ${VAR}/${VAR:-default} secret references before
+// the pipeline runs.
+func (p *Pipeline) ExpandWith(expand func(string) string) {
+	for _, step := range []*Step{p.Build, p.Test, p.Publish} {
+		if step == nil {
+			continue
+		}
+		step.Image = expand(step.Image)
+		step.Registry = expand(step.Registry)
+		for i, c := range step.Command {
+			step.Command[i] = expand(c)
+		}
+	}
+}
+
+// Load parses a pipeline from path.
+func Load(path string) (*Pipeline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pipeline %s: %w", path, err)
+	}
+	var p Pipeline
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse pipeline %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// LoadFromDir looks for bskit.yaml in dir and loads it if present. ok is
+// false (with a nil Pipeline and error) when the file doesn't exist.
+func LoadFromDir(dir string) (p *Pipeline, ok bool, err error) {
+	path := filepath.Join(dir, FileName)
+	if _, statErr := os.Stat(path); statErr != nil {
+		return nil, false, nil
+	}
+	p, err = Load(path)
+	return p, err == nil, err
+}
@@ -0,0 +1,188 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"bskit/backend/buildbackend"
+	"bskit/backend/buildlog"
+	"bskit/backend/dagger"
+)
+
+// PushFunc pushes imageRef to a registry; see backend/registry. It's
+// injected rather than imported directly so pipeline stays decoupled from
+// registry credential handling.
+type PushFunc func(ctx context.Context, imageRef string) error
+
+// StepEvent reports a pipeline stage starting or finishing, for the
+// pipeline:step:{start,end} events.
+type StepEvent struct {
+	Step     string
+	Phase    string // "start" or "end"
+	Duration time.Duration
+}
+
+// Runner executes a Pipeline's stages in order: build, test, publish.
+type Runner struct {
+	Backends     []buildbackend.Backend
+	DaggerRunner *dagger.Runner
+	Logger       *buildlog.Logger
+	Push         PushFunc
+}
+
+// NewRunner creates a Runner. push may be nil, in which case publish steps
+// are logged and skipped rather than failing the pipeline.
+func NewRunner(backends []buildbackend.Backend, daggerRunner *dagger.Runner, logger *buildlog.Logger, push PushFunc) *Runner {
+	return &Runner{Backends: backends, DaggerRunner: daggerRunner, Logger: logger, Push: push}
+}
+
+// Execute runs p's stages against repoPath, streaming progress through
+// Logger under buildID and reporting each stage's start/end via onStep.
+// branch and event are matched against each stage's `when:` block (see
+// Step.Matches); a stage whose when doesn't match is skipped rather than
+// run unconditionally.
+func (r *Runner) Execute(ctx context.Context, repoPath string, p *Pipeline, buildID, branch, event string, onStep func(StepEvent)) error {
+	// imageName is what test/publish operate on absent their own Image
+	// override. It starts as the bare repo name and, once the build stage
+	// runs, becomes whichever image that stage actually produced (see
+	// runBuild) so test/publish never reference an image that was never
+	// built.
+	imageName := filepath.Base(repoPath)
+
+	if p.Build != nil {
+		if !p.Build.Matches(branch, event) {
+			r.logSkip(buildID, "build", p.Build)
+		} else {
+			var built string
+			if err := r.runStep("build", onStep, func() error {
+				var buildErr error
+				built, buildErr = r.runBuild(ctx, repoPath, p.Build, buildID)
+				return buildErr
+			}); err != nil {
+				return err
+			}
+			imageName = built
+		}
+	}
+
+	if p.Test != nil {
+		if !p.Test.Matches(branch, event) {
+			r.logSkip(buildID, "test", p.Test)
+		} else if err := r.runStep("test", onStep, func() error {
+			return r.runTest(imageName, p.Test, buildID)
+		}); err != nil {
+			return err
+		}
+	}
+
+	if p.Publish != nil {
+		if !p.Publish.Matches(branch, event) {
+			r.logSkip(buildID, "publish", p.Publish)
+		} else if err := r.runStep("publish", onStep, func() error {
+			return r.runPublish(ctx, imageName, p.Publish, buildID)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// logSkip records that a stage was skipped because its when block didn't
+// match the triggering branch/event.
+func (r *Runner) logSkip(buildID, stage string, step *Step) {
+	if r.Logger == nil {
+		return
+	}
+	r.Logger.Write(buildID, buildlog.Info, stage, fmt.Sprintf("skipping %s: when.branch=%q when.event=%q doesn't match this run", stage, step.When.Branch, step.When.Event))
+}
+
+func (r *Runner) runStep(name string, onStep func(StepEvent), fn func() error) error {
+	if onStep != nil {
+		onStep(StepEvent{Step: name, Phase: "start"})
+	}
+	start := time.Now()
+	err := fn()
+	if onStep != nil {
+		onStep(StepEvent{Step: name, Phase: "end", Duration: time.Since(start)})
+	}
+	return err
+}
+
+// runBuild runs step's backend over every requested platform and returns
+// the name of the image that test/publish should use. Pack and Dockerfile
+// suffix their local image name with Options.ImageTag (see
+// buildbackend.Options), so when fanning out to more than one platform this
+// mirrors that suffix for the first platform in the list, which is the one
+// test/publish treat as primary.
+func (r *Runner) runBuild(ctx context.Context, repoPath string, step *Step, buildID string) (string, error) {
+	var backend buildbackend.Backend
+	if step.Backend != "" {
+		backend = buildbackend.ByName(r.Backends, step.Backend)
+		if backend == nil {
+			return "", fmt.Errorf("pipeline: unknown build backend %q", step.Backend)
+		}
+	} else {
+		backend = buildbackend.Select(r.Backends, repoPath)
+		if backend == nil {
+			return "", fmt.Errorf("pipeline: could not detect a build backend for %s", repoPath)
+		}
+	}
+
+	baseName := filepath.Base(repoPath)
+	platforms := step.Platforms
+	if len(platforms) == 0 {
+		platforms = []string{""}
+	}
+	// Tag each platform's image with its own suffix when fanning out to more
+	// than one, so e.g. "platforms: [arm64, amd64]" produces two images
+	// instead of the second build overwriting the first.
+	multi := len(platforms) > 1
+	primary := baseName
+	for i, platform := range platforms {
+		opts := buildbackend.Options{Platform: platform, BuildID: buildID, Logger: r.Logger}
+		if multi {
+			opts.ImageTag = platform
+		}
+		if err := backend.Build(ctx, repoPath, opts); err != nil {
+			return "", fmt.Errorf("pipeline: build step failed: %w", err)
+		}
+		if i == 0 && multi {
+			primary = baseName + "-" + platform
+		}
+	}
+	return primary, nil
+}
+
+func (r *Runner) runTest(imageName string, step *Step, buildID string) error {
+	image := imageName
+	if step.Image != "" {
+		image = step.Image
+	}
+	if err := r.DaggerRunner.RunCommand(image, step.Command, r.Logger, buildID); err != nil {
+		return fmt.Errorf("pipeline: test step failed: %w", err)
+	}
+	return nil
+}
+
+func (r *Runner) runPublish(ctx context.Context, imageName string, step *Step, buildID string) error {
+	ref := imageName
+	if step.Image != "" {
+		ref = step.Image
+	}
+	if step.Registry != "" {
+		ref = step.Registry + "/" + ref
+	}
+	if r.Push == nil {
+		if r.Logger != nil {
+			r.Logger.Write(buildID, buildlog.Warn, "publish", fmt.Sprintf("no push configured, skipping publish of %s", ref))
+		}
+		return nil
+	}
+	if err := r.Push(ctx, ref); err != nil {
+		return fmt.Errorf("pipeline: publish step failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package registry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"bskit/backend/auth"
+	"bskit/backend/buildlog"
+)
+
+// ghcrHost, ecrHostPattern and isGCRHost identify registries whose credentials
+// come from an existing login (GitHub, AWS, GCP CLI) rather than the
+// keyring credential store.
+const ghcrHost = "ghcr.io"
+
+var ecrHostPattern = regexp.MustCompile(`^\d+\.dkr\.ecr\.[\w-]+\.amazonaws\.com$`)
+
+func isGCRHost(host string) bool {
+	return host == "gcr.io" || strings.HasSuffix(host, ".gcr.io") || strings.HasSuffix(host, "-docker.pkg.dev")
+}
+
+// LayerProgress reports a single image layer's upload progress.
+type LayerProgress struct {
+	Layer    string
+	Status   string
+	Progress string
+}
+
+// layerLinePattern matches docker/buildkit push progress lines, e.g.
+// "5f70bf18a086: Pushing [==================>]  10.2MB/20.1MB".
+var layerLinePattern = regexp.MustCompile(`^([0-9a-f]{12}):\s+(\S.*?)(?:\s+\[[^\]]*\]\s*(\S+/\S+))?$`)
+
+// resolveCredential picks the right credential source for ref.Registry:
+// GHCR reuses the existing GitHub session, ECR and GCR shell out to the
+// cloud CLI the user already has configured, and everything else falls
+// back to the keyring-backed store.
+func resolveCredential(a *auth.Auth, ref *Ref) (username, password string, err error) {
+	switch {
+	case ref.Registry == ghcrHost:
+		token := a.Token()
+		if token == "" {
+			return "", "", fmt.Errorf("not logged in to GitHub; required to push to %s", ghcrHost)
+		}
+		return "x-access-token", token, nil
+
+	case ecrHostPattern.MatchString(ref.Registry):
+		region := strings.Split(ref.Registry, ".")[3]
+		out, err := exec.Command("aws", "ecr", "get-login-password", "--region", region).Output()
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get ECR login password: %w", err)
+		}
+		return "AWS", strings.TrimSpace(string(out)), nil
+
+	case isGCRHost(ref.Registry):
+		out, err := exec.Command("gcloud", "auth", "print-access-token").Output()
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get GCR access token: %w", err)
+		}
+		return "oauth2accesstoken", strings.TrimSpace(string(out)), nil
+
+	default:
+		cred, ok, err := a.Credentials.GetRegistryCredential(ref.Registry)
+		if err != nil {
+			return "", "", err
+		}
+		if !ok {
+			return "", "", fmt.Errorf("no credentials stored for registry %s", ref.Registry)
+		}
+		return cred.Username, cred.Password, nil
+	}
+}
+
+// Push logs in to ref.Registry and pushes ref, streaming progress to logger
+// under the "push" phase and reporting per-layer progress via onLayer.
+func Push(ctx context.Context, a *auth.Auth, ref *Ref, logger *buildlog.Logger, buildID string, onLayer func(LayerProgress)) error {
+	username, password, err := resolveCredential(a, ref)
+	if err != nil {
+		return fmt.Errorf("failed to resolve credentials for %s: %w", ref.Registry, err)
+	}
+
+	login := exec.CommandContext(ctx, "docker", "login", ref.Registry, "-u", username, "--password-stdin")
+	login.Stdin = strings.NewReader(password)
+	if out, err := login.CombinedOutput(); err != nil {
+		return fmt.Errorf("docker login to %s failed: %s: %w", ref.Registry, strings.TrimSpace(string(out)), err)
+	}
+
+	push := exec.CommandContext(ctx, "docker", "push", ref.String())
+	w := &pushWriter{logger: logger, buildID: buildID, onLayer: onLayer}
+	push.Stdout = w
+	push.Stderr = w
+
+	if err := push.Run(); err != nil {
+		return fmt.Errorf("docker push %s failed: %w", ref.String(), err)
+	}
+	return nil
+}
+
+// pushWriter adapts docker push's combined output into log lines and,
+// where a line matches layerLinePattern, a LayerProgress callback.
+type pushWriter struct {
+	logger  *buildlog.Logger
+	buildID string
+	onLayer func(LayerProgress)
+}
+
+func (w *pushWriter) Write(p []byte) (int, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(p))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if w.logger != nil {
+			w.logger.Write(w.buildID, buildlog.Info, "push", line)
+		}
+		if m := layerLinePattern.FindStringSubmatch(line); m != nil && w.onLayer != nil {
+			w.onLayer(LayerProgress{Layer: m[1], Status: m[2], Progress: m[3]})
+		}
+	}
+	return len(p), nil
+}
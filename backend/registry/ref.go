@@ -0,0 +1,97 @@
+// Package registry parses image references and pushes images to Docker
+// Hub, GHCR, ECR, and GCR, resolving credentials via backend/auth instead
+// of treating the image name as an opaque string.
+package registry
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// defaultRegistry is used when a reference has no explicit registry host,
+// matching Docker's own convention.
+const defaultRegistry = "docker.io"
+
+// tagPattern matches the characters Docker allows in a tag.
+var tagPattern = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}$`)
+
+// digestPattern matches a content digest, e.g. sha256:<64 hex chars>.
+var digestPattern = regexp.MustCompile(`^[a-z0-9]+:[a-f0-9]{32,}$`)
+
+// Ref is a parsed image reference: [registry/]repository[:tag][@digest].
+type Ref struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// String renders ref back into an image reference string.
+func (r *Ref) String() string {
+	s := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// ParseRef parses an image reference such as "nginx", "ghcr.io/org/app:v1",
+// "localhost:5000/team/app:v1", or "repo@sha256:...". It defaults Tag to
+// "latest" when neither a tag nor a digest is given, and rejects tags with
+// invalid characters.
+func ParseRef(imageRef string) (*Ref, error) {
+	if imageRef == "" {
+		return nil, fmt.Errorf("image reference is empty")
+	}
+
+	rest := imageRef
+	var digest string
+	if i := strings.LastIndex(rest, "@"); i != -1 {
+		digest = rest[i+1:]
+		if !digestPattern.MatchString(digest) {
+			return nil, fmt.Errorf("invalid digest %q in image reference %q", digest, imageRef)
+		}
+		rest = rest[:i]
+	}
+
+	registryHost := defaultRegistry
+	repoAndTag := rest
+	if i := strings.Index(rest, "/"); i != -1 {
+		candidate := rest[:i]
+		if strings.ContainsAny(candidate, ".:") || candidate == "localhost" {
+			registryHost = candidate
+			repoAndTag = rest[i+1:]
+		}
+	}
+	if repoAndTag == "" {
+		return nil, fmt.Errorf("image reference %q has no repository", imageRef)
+	}
+
+	repository := repoAndTag
+	tag := ""
+	// A ":" after the last "/" separates the tag; one before it (e.g. a
+	// registry port) was already consumed above.
+	lastSlash := strings.LastIndex(repoAndTag, "/")
+	if i := strings.LastIndex(repoAndTag, ":"); i != -1 && i > lastSlash {
+		repository = repoAndTag[:i]
+		tag = repoAndTag[i+1:]
+		if !tagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("invalid tag %q in image reference %q", tag, imageRef)
+		}
+	}
+
+	if tag == "" && digest == "" {
+		tag = "latest"
+	}
+
+	return &Ref{
+		Registry:   registryHost,
+		Repository: repository,
+		Tag:        tag,
+		Digest:     digest,
+	}, nil
+}
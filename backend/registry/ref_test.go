@@ -0,0 +1,96 @@
+package registry
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRef(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+
+	tests := []struct {
+		name    string
+		ref     string
+		want    Ref
+		wantErr bool
+	}{
+		{
+			name: "bare name defaults registry and tag",
+			ref:  "nginx",
+			want: Ref{Registry: "docker.io", Repository: "nginx", Tag: "latest"},
+		},
+		{
+			name: "org/repo with no registry host",
+			ref:  "org/app",
+			want: Ref{Registry: "docker.io", Repository: "org/app", Tag: "latest"},
+		},
+		{
+			name: "registry host with explicit tag",
+			ref:  "ghcr.io/org/app:v1",
+			want: Ref{Registry: "ghcr.io", Repository: "org/app", Tag: "v1"},
+		},
+		{
+			name: "localhost host with port",
+			ref:  "localhost:5000/team/app:v1",
+			want: Ref{Registry: "localhost:5000", Repository: "team/app", Tag: "v1"},
+		},
+		{
+			name: "non-localhost host with port",
+			ref:  "myregistry.example.com:5000/app:v2",
+			want: Ref{Registry: "myregistry.example.com:5000", Repository: "app", Tag: "v2"},
+		},
+		{
+			name: "localhost with no port",
+			ref:  "localhost/app",
+			want: Ref{Registry: "localhost", Repository: "app", Tag: "latest"},
+		},
+		{
+			name: "digest with no tag",
+			ref:  "org/app@" + digest,
+			want: Ref{Registry: "docker.io", Repository: "org/app", Digest: digest},
+		},
+		{
+			name: "tag and digest together",
+			ref:  "org/app:v1@" + digest,
+			want: Ref{Registry: "docker.io", Repository: "org/app", Tag: "v1", Digest: digest},
+		},
+		{
+			name:    "empty reference",
+			ref:     "",
+			wantErr: true,
+		},
+		{
+			name:    "no repository after host",
+			ref:     "ghcr.io/",
+			wantErr: true,
+		},
+		{
+			name:    "invalid tag characters",
+			ref:     "org/app:bad tag",
+			wantErr: true,
+		},
+		{
+			name:    "invalid digest",
+			ref:     "org/app@sha256:tooshort",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRef(tt.ref)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseRef(%q) = %+v, want error", tt.ref, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRef(%q) returned unexpected error: %v", tt.ref, err)
+			}
+			if *got != tt.want {
+				t.Errorf("ParseRef(%q) = %+v, want %+v", tt.ref, *got, tt.want)
+			}
+		})
+	}
+}
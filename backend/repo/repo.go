@@ -0,0 +1,93 @@
+// Package repo clones GitHub repositories locally and tracks them for
+// bskit's build and watch workflows.
+package repo
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// RepoManager clones and tracks repositories under a local base dir, and
+// runs the background watch daemon described in watch.go.
+type RepoManager struct {
+	baseDir string
+
+	watchMu     sync.Mutex
+	watches     map[string]*WatchConfig
+	runners     map[string]*watchRunner
+	onUpdate    func(RepoUpdate)
+	onAutoBuild func(repoPath, branch string)
+}
+
+// NewRepoManager creates a RepoManager rooted under the user's config dir.
+func NewRepoManager() (*RepoManager, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve user config dir: %w", err)
+	}
+	baseDir := filepath.Join(configDir, "bskit", "repos")
+	if err := os.MkdirAll(baseDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create repos dir: %w", err)
+	}
+	return &RepoManager{
+		baseDir: baseDir,
+		watches: make(map[string]*WatchConfig),
+		runners: make(map[string]*watchRunner),
+	}, nil
+}
+
+// RepoStatus reports whether a repository URL has already been cloned.
+type RepoStatus struct {
+	Cloned bool
+	Path   string
+}
+
+func (r *RepoManager) pathFor(url string) string {
+	name := strings.TrimSuffix(filepath.Base(url), ".git")
+	return filepath.Join(r.baseDir, name)
+}
+
+// CloneRepo clones url into the manager's base dir and returns the local
+// path, or returns the existing path if it's already cloned.
+func (r *RepoManager) CloneRepo(url string) (string, error) {
+	path := r.pathFor(url)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	cmd := exec.Command("git", "clone", url, path)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to clone %s: %s: %w", url, strings.TrimSpace(string(out)), err)
+	}
+	return path, nil
+}
+
+// GetRepoStatus checks whether url has already been cloned.
+func (r *RepoManager) GetRepoStatus(url string) (*RepoStatus, error) {
+	path := r.pathFor(url)
+	_, err := os.Stat(path)
+	return &RepoStatus{Cloned: err == nil, Path: path}, nil
+}
+
+// ListClonedRepos returns the local paths of every cloned repository.
+func (r *RepoManager) ListClonedRepos() ([]string, error) {
+	entries, err := os.ReadDir(r.baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cloned repos: %w", err)
+	}
+	var paths []string
+	for _, e := range entries {
+		if e.IsDir() {
+			paths = append(paths, filepath.Join(r.baseDir, e.Name()))
+		}
+	}
+	return paths, nil
+}
+
+// DeleteRepo removes the cloned repository at path.
+func DeleteRepo(path string) error {
+	return os.RemoveAll(path)
+}
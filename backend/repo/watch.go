@@ -0,0 +1,254 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WatchConfig describes a repo's background sync: which branches to poll,
+// how often, and whether an advancing branch should trigger a build.
+type WatchConfig struct {
+	RepoPath    string   `json:"repoPath"`
+	Branches    []string `json:"branches"`
+	IntervalSec int      `json:"intervalSec"`
+	AutoBuild   bool     `json:"autoBuild"`
+}
+
+// RepoUpdate reports that a watched branch advanced to a new commit.
+type RepoUpdate struct {
+	RepoPath string
+	Branch   string
+	OldSHA   string
+	NewSHA   string
+}
+
+// watchRunner drives the periodic fetch loop for one watched repo.
+type watchRunner struct {
+	cancel context.CancelFunc
+
+	fetchMu  sync.Mutex
+	fetching bool
+
+	lastSHA map[string]string
+	backoff time.Duration
+}
+
+// watchesFileName is the persisted config under the user config dir.
+const watchesFileName = "watches.json"
+
+// Start loads any previously persisted watches and resumes them, and
+// registers the callbacks used by SetRepoWatch for newly added ones.
+// onUpdate fires whenever a watched branch advances; onAutoBuild fires
+// after onUpdate for watches with AutoBuild set.
+func (r *RepoManager) Start(ctx context.Context, onUpdate func(RepoUpdate), onAutoBuild func(repoPath, branch string)) error {
+	r.watchMu.Lock()
+	r.onUpdate = onUpdate
+	r.onAutoBuild = onAutoBuild
+	configs, err := r.loadWatches()
+	r.watchMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		r.startWatch(ctx, cfg)
+	}
+	return nil
+}
+
+// SetRepoWatch persists cfg and (re)starts its background fetch loop. An
+// IntervalSec of 0 stops and removes any existing watch for repoPath.
+func (r *RepoManager) SetRepoWatch(ctx context.Context, repoPath string, branches []string, intervalSec int, autoBuild bool) error {
+	r.watchMu.Lock()
+	if runner, ok := r.runners[repoPath]; ok {
+		runner.cancel()
+		delete(r.runners, repoPath)
+	}
+	if intervalSec <= 0 {
+		delete(r.watches, repoPath)
+		err := r.saveWatchesLocked()
+		r.watchMu.Unlock()
+		return err
+	}
+	cfg := &WatchConfig{RepoPath: repoPath, Branches: branches, IntervalSec: intervalSec, AutoBuild: autoBuild}
+	r.watches[repoPath] = cfg
+	err := r.saveWatchesLocked()
+	r.watchMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	r.startWatch(ctx, cfg)
+	return nil
+}
+
+// GetRepoWatch returns the watch configured for repoPath, if any.
+func (r *RepoManager) GetRepoWatch(repoPath string) (*WatchConfig, error) {
+	r.watchMu.Lock()
+	defer r.watchMu.Unlock()
+	cfg, ok := r.watches[repoPath]
+	if !ok {
+		return nil, fmt.Errorf("no watch configured for %s", repoPath)
+	}
+	return cfg, nil
+}
+
+func (r *RepoManager) startWatch(ctx context.Context, cfg *WatchConfig) {
+	watchCtx, cancel := context.WithCancel(ctx)
+	runner := &watchRunner{
+		cancel:  cancel,
+		lastSHA: make(map[string]string),
+		backoff: time.Duration(cfg.IntervalSec) * time.Second,
+	}
+
+	r.watchMu.Lock()
+	r.runners[cfg.RepoPath] = runner
+	r.watchMu.Unlock()
+
+	go r.runWatch(watchCtx, cfg, runner)
+}
+
+func (r *RepoManager) runWatch(ctx context.Context, cfg *WatchConfig, runner *watchRunner) {
+	ticker := time.NewTicker(time.Duration(cfg.IntervalSec) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.poll(ctx, cfg, runner)
+		}
+	}
+}
+
+// poll fetches cfg.RepoPath and reports any branch that advanced. It
+// coalesces concurrent ticks (skipping one already in flight) and backs
+// off on fetch errors, e.g. transient network issues.
+func (r *RepoManager) poll(ctx context.Context, cfg *WatchConfig, runner *watchRunner) {
+	runner.fetchMu.Lock()
+	if runner.fetching {
+		runner.fetchMu.Unlock()
+		return
+	}
+	runner.fetching = true
+	runner.fetchMu.Unlock()
+	defer func() {
+		runner.fetchMu.Lock()
+		runner.fetching = false
+		runner.fetchMu.Unlock()
+	}()
+
+	cmd := exec.CommandContext(ctx, "git", "-C", cfg.RepoPath, "fetch", "origin")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		runner.backoff = nextBackoff(runner.backoff, time.Duration(cfg.IntervalSec)*time.Second)
+		_ = out
+		time.Sleep(runner.backoff)
+		return
+	}
+	runner.backoff = time.Duration(cfg.IntervalSec) * time.Second
+
+	for _, branch := range cfg.Branches {
+		newSHA, err := revParse(ctx, cfg.RepoPath, "origin/"+branch)
+		if err != nil {
+			continue
+		}
+		oldSHA := runner.lastSHA[branch]
+		runner.lastSHA[branch] = newSHA
+		if oldSHA == "" || oldSHA == newSHA {
+			continue
+		}
+
+		if r.onUpdate != nil {
+			r.onUpdate(RepoUpdate{RepoPath: cfg.RepoPath, Branch: branch, OldSHA: oldSHA, NewSHA: newSHA})
+		}
+		if cfg.AutoBuild && r.onAutoBuild != nil {
+			r.onAutoBuild(cfg.RepoPath, branch)
+		}
+	}
+}
+
+func revParse(ctx context.Context, repoPath, ref string) (string, error) {
+	out, err := exec.CommandContext(ctx, "git", "-C", repoPath, "rev-parse", ref).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s in %s: %w", ref, repoPath, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// nextBackoff doubles delay, capped at 10x base, to back off on repeated
+// fetch errors without drifting arbitrarily far from the configured interval.
+func nextBackoff(delay, base time.Duration) time.Duration {
+	if delay < base {
+		delay = base
+	}
+	next := delay * 2
+	if max := base * 10; next > max {
+		next = max
+	}
+	return next
+}
+
+func (r *RepoManager) watchesPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve user config dir: %w", err)
+	}
+	return filepath.Join(configDir, "bskit", watchesFileName), nil
+}
+
+// loadWatches reads the persisted watch config file, if any. Caller must
+// hold watchMu.
+func (r *RepoManager) loadWatches() ([]*WatchConfig, error) {
+	path, err := r.watchesPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watch config: %w", err)
+	}
+
+	var configs []*WatchConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("failed to parse watch config: %w", err)
+	}
+	for _, cfg := range configs {
+		r.watches[cfg.RepoPath] = cfg
+	}
+	return configs, nil
+}
+
+// saveWatchesLocked persists the current watch set. Caller must hold watchMu.
+func (r *RepoManager) saveWatchesLocked() error {
+	path, err := r.watchesPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config dir: %w", err)
+	}
+
+	configs := make([]*WatchConfig, 0, len(r.watches))
+	for _, cfg := range r.watches {
+		configs = append(configs, cfg)
+	}
+	data, err := json.MarshalIndent(configs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode watch config: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write watch config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package secrets
+
+import (
+	"os"
+	"regexp"
+)
+
+// varPattern matches ${VAR} and ${VAR:-default}.
+var varPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// Expand replaces ${VAR} and ${VAR:-default} references in value. Names are
+// resolved against the Store first, falling back to the OS environment;
+// an unresolved reference with no default expands to "".
+func (s *Store) Expand(value string) string {
+	return varPattern.ReplaceAllStringFunc(value, func(match string) string {
+		groups := varPattern.FindStringSubmatch(match)
+		name, hasDefault, def := groups[1], groups[2] != "", groups[3]
+
+		if v, err := s.Get(name); err == nil {
+			return v
+		}
+		if v, ok := os.LookupEnv(name); ok {
+			return v
+		}
+		if hasDefault {
+			return def
+		}
+		return ""
+	})
+}
+
+// ExpandMap applies Expand to every value in m, returning a new map.
+func (s *Store) ExpandMap(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = s.Expand(v)
+	}
+	return out
+}
+
+// ExpandAll applies Expand to every string in values, returning a new slice.
+func (s *Store) ExpandAll(values []string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = s.Expand(v)
+	}
+	return out
+}
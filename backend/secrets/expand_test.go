@@ -0,0 +1,47 @@
+package secrets
+
+import (
+	"os"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	t.Setenv("BSKIT_TEST_EXPAND_SET", "env-value")
+	os.Unsetenv("BSKIT_TEST_EXPAND_UNSET")
+
+	s := NewStore()
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"no references", "plain string", "plain string"},
+		{"set env var", "${BSKIT_TEST_EXPAND_SET}", "env-value"},
+		{"unset env var, no default", "${BSKIT_TEST_EXPAND_UNSET}", ""},
+		{"unset env var, default used", "${BSKIT_TEST_EXPAND_UNSET:-fallback}", "fallback"},
+		{"set env var, default ignored", "${BSKIT_TEST_EXPAND_SET:-fallback}", "env-value"},
+		{"empty default", "${BSKIT_TEST_EXPAND_UNSET:-}", ""},
+		{"embedded in surrounding text", "prefix-${BSKIT_TEST_EXPAND_SET}-suffix", "prefix-env-value-suffix"},
+		{"multiple references", "${BSKIT_TEST_EXPAND_SET}/${BSKIT_TEST_EXPAND_UNSET:-def}", "env-value/def"},
+		{"unterminated reference left alone", "${BSKIT_TEST_EXPAND_SET", "${BSKIT_TEST_EXPAND_SET"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Expand(tt.value); got != tt.want {
+				t.Errorf("Expand(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandMap(t *testing.T) {
+	t.Setenv("BSKIT_TEST_EXPAND_SET", "env-value")
+
+	s := NewStore()
+	got := s.ExpandMap(map[string]string{"KEY": "${BSKIT_TEST_EXPAND_SET}"})
+	if got["KEY"] != "env-value" {
+		t.Errorf("ExpandMap()[KEY] = %q, want %q", got["KEY"], "env-value")
+	}
+}
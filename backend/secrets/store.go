@@ -0,0 +1,120 @@
+// Package secrets stores registry tokens, signing keys, and other
+// per-project config in the OS keyring, and expands ${VAR} / ${VAR:-default}
+// references to them in build inputs before those inputs reach a build
+// backend or the dagger runner.
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/zalando/go-keyring"
+)
+
+// secretsService and indexService namespace bskit's secret entries in the
+// OS keyring. The index is needed because keyring backends generally can't
+// enumerate entries by service, only fetch by exact name.
+const (
+	secretsService = "bskit-secrets"
+	indexService   = "bskit-secrets-index"
+	indexUser      = "names"
+)
+
+// Store persists named secrets in the OS keyring. Once a value is set, it's
+// never returned to the frontend; only names are listed.
+type Store struct{}
+
+// NewStore returns a Store backed by the OS keyring.
+func NewStore() *Store {
+	return &Store{}
+}
+
+// Set stores value under name, overwriting any existing value.
+func (s *Store) Set(name, value string) error {
+	if err := keyring.Set(secretsService, name, value); err != nil {
+		return fmt.Errorf("failed to store secret %q: %w", name, err)
+	}
+	names, err := s.readIndex()
+	if err != nil {
+		return err
+	}
+	if !contains(names, name) {
+		names = append(names, name)
+		sort.Strings(names)
+		if err := s.writeIndex(names); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get returns the value stored for name.
+func (s *Store) Get(name string) (string, error) {
+	value, err := keyring.Get(secretsService, name)
+	if err == keyring.ErrNotFound {
+		return "", fmt.Errorf("secret %q not found", name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret %q: %w", name, err)
+	}
+	return value, nil
+}
+
+// List returns the names of every stored secret, without their values.
+func (s *Store) List() ([]string, error) {
+	return s.readIndex()
+}
+
+// Delete removes the secret stored under name.
+func (s *Store) Delete(name string) error {
+	if err := keyring.Delete(secretsService, name); err != nil && err != keyring.ErrNotFound {
+		return fmt.Errorf("failed to delete secret %q: %w", name, err)
+	}
+	names, err := s.readIndex()
+	if err != nil {
+		return err
+	}
+	out := names[:0]
+	for _, n := range names {
+		if n != name {
+			out = append(out, n)
+		}
+	}
+	return s.writeIndex(out)
+}
+
+func (s *Store) readIndex() ([]string, error) {
+	data, err := keyring.Get(indexService, indexUser)
+	if err == keyring.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret index: %w", err)
+	}
+	var names []string
+	if err := json.Unmarshal([]byte(data), &names); err != nil {
+		return nil, fmt.Errorf("failed to parse secret index: %w", err)
+	}
+	return names, nil
+}
+
+func (s *Store) writeIndex(names []string) error {
+	data, err := json.Marshal(names)
+	if err != nil {
+		return fmt.Errorf("failed to encode secret index: %w", err)
+	}
+	if err := keyring.Set(indexService, indexUser, string(data)); err != nil {
+		return fmt.Errorf("failed to write secret index: %w", err)
+	}
+	return nil
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}